@@ -17,14 +17,50 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"flag"
+
+	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
+	svcinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/signals"
+
+	wasmmoduleinformer "github.com/cardil/knative-serving-wasm/pkg/client/injection/informers/wasm/v1alpha1/wasmmodule"
+	"github.com/cardil/knative-serving-wasm/pkg/health"
+
 	// The set of controllers this controller process runs.
 	"github.com/cardil/knative-serving-wasm/pkg/reconciler/wasmmodule"
 	// This defines the shared main for injected controllers.
 	"knative.dev/pkg/injection/sharedmain"
 )
 
+var healthPort = flag.Int("health-port", 8081,
+	"The port on which to serve the /healthz and /readyz probes.")
+
 func main() {
-	sharedmain.Main("controller",
-		wasmmodule.NewController,
+	// The probes served by newController replace the default ones of the
+	// shared main, so that a single server reports the informer sync.
+	ctx := sharedmain.WithHealthProbesDisabled(signals.NewContext())
+	sharedmain.MainWithContext(ctx, "controller",
+		newController,
+	)
+}
+
+// newController creates the WasmModule controller and serves the health
+// probes, which report ready once its informers have synced.
+func newController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	impl := wasmmodule.NewController(ctx, cmw)
+	handler := health.Handler(
+		wasmmoduleinformer.Get(ctx).Informer().HasSynced,
+		svcinformer.Get(ctx).Informer().HasSynced,
+		podinformer.Get(ctx).Informer().HasSynced,
 	)
+	go func() {
+		if err := health.Serve(ctx, *healthPort, handler); err != nil {
+			logging.FromContext(ctx).Fatal("Error serving health probes: ", err)
+		}
+	}()
+	return impl
 }
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// docs is a tool to generate a Markdown field reference of the API types.
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("Usage: docs <api package dir>")
+	}
+	out := bufio.NewWriter(os.Stdout)
+	if err := render(out, os.Args[1]); err != nil {
+		log.Fatal("Error rendering docs: ", err)
+	}
+	if err := out.Flush(); err != nil {
+		log.Fatal("Error writing docs: ", err)
+	}
+}
+
+// render writes the field reference of all exported struct types declared in
+// the Go package in dir.
+func render(w io.Writer, dir string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_test.go") &&
+			!strings.HasPrefix(name, "zz_generated")
+	}, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		// Types are sorted by name, so the output is deterministic.
+		dpkg := doc.New(pkg, dir, doc.PreserveAST)
+		fmt.Fprintf(w, "# API reference: %s\n", dpkg.Name)
+		for _, typ := range dpkg.Types {
+			renderType(w, typ)
+		}
+	}
+	return nil
+}
+
+func renderType(w io.Writer, typ *doc.Type) {
+	for _, spec := range typ.Decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || !ts.Name.IsExported() {
+			continue
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "\n## %s\n\n", ts.Name.Name)
+		if text := description(typ.Doc); text != "" {
+			fmt.Fprintf(w, "%s\n\n", text)
+		}
+		fmt.Fprintln(w, "| Field | Type | Description |")
+		fmt.Fprintln(w, "| --- | --- | --- |")
+		for _, field := range st.Fields.List {
+			renderField(w, field)
+		}
+	}
+}
+
+func renderField(w io.Writer, field *ast.Field) {
+	name := jsonName(field)
+	if name == "-" {
+		return
+	}
+	if name == "" {
+		name = "_(inline)_"
+	} else {
+		name = "`" + name + "`"
+	}
+	docText := field.Doc.Text()
+	if strings.Contains(docText, "+optional") {
+		name += " _(optional)_"
+	}
+	fmt.Fprintf(w, "| %s | `%s` | %s |\n", name,
+		types.ExprString(field.Type),
+		strings.ReplaceAll(description(docText), "|", "\\|"))
+}
+
+// jsonName returns the JSON name of the field, or an empty string for
+// inlined fields.
+func jsonName(field *ast.Field) string {
+	if field.Tag != nil {
+		if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+			name, opts, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+			if name != "" || opts == "inline" {
+				return name
+			}
+		}
+	}
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	return ""
+}
+
+// description joins a doc comment into a single line, skipping the
+// code generation markers.
+func description(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "+") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
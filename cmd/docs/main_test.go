@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	var first, second strings.Builder
+	if err := render(&first, "../../pkg/apis/wasm/v1alpha1"); err != nil {
+		t.Fatal("render() =", err)
+	}
+	if err := render(&second, "../../pkg/apis/wasm/v1alpha1"); err != nil {
+		t.Fatal("render() =", err)
+	}
+	if first.String() != second.String() {
+		t.Error("render() output isn't deterministic")
+	}
+	for _, want := range []string{
+		"## WasmModuleSpec",
+		"| `serviceName` _(optional)_ | `string` |",
+		"## WasmModuleStatus",
+	} {
+		if !strings.Contains(first.String(), want) {
+			t.Errorf("render() output is missing %q:\n%s", want, first.String())
+		}
+	}
+}
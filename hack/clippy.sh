@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+
+# Copyright 2024 The Knative Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -Eeuo pipefail
+
+readonly REPO_ROOT_DIR="$(git rev-parse --show-toplevel)"
+
+# Clippy findings are denied, so any of them fails the check.
+ret=0
+for module in "${REPO_ROOT_DIR}"/examples/modules/*/; do
+  echo "Linting ${module}"
+  cargo clippy --manifest-path "${module}Cargo.toml" -- -D warnings || ret=1
+done
+
+if [[ $ret -eq 0 ]]
+then
+  echo "No clippy findings."
+else
+  echo "ERROR: cargo clippy failed. Please fix the findings in the failing modules"
+  exit 1
+fi
@@ -0,0 +1,69 @@
+#!/usr/bin/env bash
+
+# Copyright 2024 The Knative Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Assembles a minimal Helm chart from the manifests in config/, so the chart
+# can't drift from them. The images are taken from values.yaml.
+
+set -Eeuo pipefail
+
+readonly REPO_ROOT_DIR="$(git rev-parse --show-toplevel)"
+readonly CHART_DIR="${REPO_ROOT_DIR}/build/output/chart"
+readonly KO_IMAGE_PREFIX="ko://github.com/cardil/knative-serving-wasm/cmd/"
+
+# The chart is verified with helm, so fail before writing anything without it.
+if ! command -v helm > /dev/null; then
+  echo "ERROR: helm not found. Please install it to verify the chart: https://helm.sh/docs/intro/install/"
+  exit 1
+fi
+
+rm -rf "${CHART_DIR}"
+mkdir -p "${CHART_DIR}/crds" "${CHART_DIR}/templates"
+
+cat > "${CHART_DIR}/Chart.yaml" <<CHART
+apiVersion: v2
+name: knative-serving-wasm
+description: Runs WebAssembly modules on Knative Serving.
+type: application
+version: 0.1.0
+CHART
+
+cat > "${CHART_DIR}/values.yaml" <<VALUES
+# The images of the binaries under cmd/, e.g. as printed by:
+# ko build ./cmd/controller
+images:
+  controller: ""
+  webhook: ""
+VALUES
+
+# Helm installs the CRD from crds/ before rendering the templates.
+cp "${REPO_ROOT_DIR}/config/300-wasmmodule.yaml" "${CHART_DIR}/crds/"
+
+for manifest in "${REPO_ROOT_DIR}"/config/*.yaml; do
+  name="$(basename "${manifest}")"
+  [[ "${name}" == "300-wasmmodule.yaml" ]] && continue
+  # Braces in the manifests, like the request log template, are escaped
+  # so Helm renders them verbatim.
+  sed -e 's/{{/__LBRACES__/g' -e 's/}}/__RBRACES__/g' \
+    -e 's/__LBRACES__/{{ "{{" }}/g' -e 's/__RBRACES__/{{ "}}" }}/g' \
+    -e "s|${KO_IMAGE_PREFIX}\([a-z]*\)|{{ required \"images.\1 is required\" .Values.images.\1 }}|" \
+    "${manifest}" > "${CHART_DIR}/templates/${name}"
+done
+
+images=(--set images.controller=example.com/controller --set images.webhook=example.com/webhook)
+helm lint "${CHART_DIR}" "${images[@]}"
+helm template "${CHART_DIR}" "${images[@]}" > /dev/null
+
+echo "Chart written to ${CHART_DIR}"
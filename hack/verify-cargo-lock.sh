@@ -0,0 +1,35 @@
+#!/usr/bin/env bash
+
+# Copyright 2024 The Knative Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -Eeuo pipefail
+
+readonly REPO_ROOT_DIR="$(git rev-parse --show-toplevel)"
+
+# With --locked, cargo fails instead of updating a stale Cargo.lock.
+ret=0
+for module in "${REPO_ROOT_DIR}"/examples/modules/*/; do
+  echo "Verifying ${module}Cargo.lock"
+  cargo metadata --locked --format-version 1 \
+    --manifest-path "${module}Cargo.toml" > /dev/null || ret=1
+done
+
+if [[ $ret -eq 0 ]]
+then
+  echo "Cargo.lock files up to date."
+else
+  echo "ERROR: Cargo.lock files are out of date. Please run cargo update -w in the failing modules"
+  exit 1
+fi
@@ -22,5 +22,7 @@ import (
 
 // SetDefaults implements apis.Defaultable
 func (as *WasmModule) SetDefaults(ctx context.Context) {
-	// Nothing to default.
+	// The spec can't see the module name, so it's defaulted here. A module
+	// name that isn't a valid Service name is left for validation to reject.
+	as.Spec.ServiceName = as.EffectiveServiceName()
 }
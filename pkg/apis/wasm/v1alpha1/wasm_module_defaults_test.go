@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWasmModuleDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		moduleName string
+		spec       WasmModuleSpec
+		want       string
+	}{{
+		name:       "service name defaults to module name",
+		moduleName: "reverse-text",
+		spec:       WasmModuleSpec{},
+		want:       "reverse-text",
+	}, {
+		name:       "service name kept",
+		moduleName: "reverse-text",
+		spec:       WasmModuleSpec{ServiceName: "strreverse"},
+		want:       "strreverse",
+	}, {
+		name:       "module name isn't a valid service name",
+		moduleName: "1-reverse-text",
+		spec:       WasmModuleSpec{},
+		want:       "",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			module := &WasmModule{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.moduleName},
+				Spec:       tc.spec,
+			}
+			module.SetDefaults(context.Background())
+			if got := module.Spec.ServiceName; got != tc.want {
+				t.Errorf("ServiceName = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
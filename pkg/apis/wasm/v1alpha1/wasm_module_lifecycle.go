@@ -18,10 +18,39 @@ package v1alpha1
 
 import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
-var condSet = apis.NewLivingConditionSet()
+// Reasons set on the WasmModule conditions. They are part of the API, so
+// automation can rely on them to tell failure classes apart.
+const (
+	// ReasonServiceUnavailable is set on the ServiceCreated and Ready
+	// conditions when the referenced Service doesn't exist.
+	ReasonServiceUnavailable = "ServiceUnavailable"
+
+	// ReasonNoReadyPods is set on the ServiceReady and Ready conditions while
+	// none of the pods selected by the Service is ready.
+	ReasonNoReadyPods = "NoReadyPods"
+
+	// ReasonReadinessGateNotPassed is set on the FunctionalReady condition
+	// until the module serves its readiness gate successfully.
+	ReasonReadinessGateNotPassed = "ReadinessGateNotPassed"
+
+	// ReasonProgressDeadlineExceeded is set on the Ready condition when the
+	// module doesn't become ready within its progress deadline.
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+	// ReasonInvalidSpec is set on the Ready condition when the module can't
+	// be reconciled as specified, e.g. it resolves to no Service name.
+	ReasonInvalidSpec = "InvalidSpec"
+)
+
+var condSet = apis.NewLivingConditionSet(
+	WasmModuleConditionServiceCreated,
+	WasmModuleConditionServiceReady,
+	WasmModuleConditionFunctionalReady,
+)
 
 // GetGroupVersionKind implements kmeta.OwnerRefable
 func (*WasmModule) GetGroupVersionKind() schema.GroupVersionKind {
@@ -33,18 +62,105 @@ func (as *WasmModule) GetConditionSet() apis.ConditionSet {
 	return condSet
 }
 
+// EffectiveServiceName returns the name of the Service the module is bound
+// to: spec.serviceName, or the module name if that's a valid Service name.
+// It returns an empty string if neither can be used.
+func (as *WasmModule) EffectiveServiceName() string {
+	if as.Spec.ServiceName != "" {
+		return as.Spec.ServiceName
+	}
+	if len(validation.IsDNS1035Label(as.Name)) == 0 {
+		return as.Name
+	}
+	return ""
+}
+
 // InitializeConditions sets the initial values to the conditions.
 func (ass *WasmModuleStatus) InitializeConditions() {
 	condSet.Manage(ass).InitializeConditions()
 }
 
+// MarkServiceUnavailable marks the Service the module is bound to as
+// missing. Its readiness is unknown until it shows up.
 func (ass *WasmModuleStatus) MarkServiceUnavailable(name string) {
+	condSet.Manage(ass).MarkUnknown(
+		WasmModuleConditionServiceReady,
+		ReasonServiceUnavailable,
+		"Service %q wasn't found.", name)
 	condSet.Manage(ass).MarkFalse(
-		WasmModuleConditionReady,
-		"ServiceUnavailable",
+		WasmModuleConditionServiceCreated,
+		ReasonServiceUnavailable,
 		"Service %q wasn't found.", name)
 }
 
+// MarkServiceAvailable marks the Service the module is bound to as created.
 func (ass *WasmModuleStatus) MarkServiceAvailable() {
-	condSet.Manage(ass).MarkTrue(WasmModuleConditionReady)
+	condSet.Manage(ass).MarkTrue(WasmModuleConditionServiceCreated)
+}
+
+// MarkServiceNotReady marks the Service as existing, but not yet able to
+// serve the module.
+func (ass *WasmModuleStatus) MarkServiceNotReady(reason, message string) {
+	condSet.Manage(ass).MarkUnknown(WasmModuleConditionServiceReady, reason, message)
+}
+
+// MarkServiceReady marks the Service as able to serve the module.
+func (ass *WasmModuleStatus) MarkServiceReady() {
+	condSet.Manage(ass).MarkTrue(WasmModuleConditionServiceReady)
+}
+
+// MarkFunctionalReady marks the module as having served a successful request.
+func (ass *WasmModuleStatus) MarkFunctionalReady() {
+	condSet.Manage(ass).MarkTrue(WasmModuleConditionFunctionalReady)
+}
+
+// MarkFunctionalNotReady marks the module as not yet serving its readiness
+// gate successfully.
+func (ass *WasmModuleStatus) MarkFunctionalNotReady(messageFormat string, messageA ...interface{}) {
+	condSet.Manage(ass).MarkUnknown(
+		WasmModuleConditionFunctionalReady,
+		ReasonReadinessGateNotPassed,
+		messageFormat, messageA...)
+}
+
+// MarkProgressDeadlineExceeded marks the module as failed to become ready
+// within its progress deadline.
+func (ass *WasmModuleStatus) MarkProgressDeadlineExceeded(seconds int32) {
+	condSet.Manage(ass).MarkFalse(
+		WasmModuleConditionReady,
+		ReasonProgressDeadlineExceeded,
+		"WasmModule did not become ready within %d seconds.", seconds)
+}
+
+// MarkInvalidSpec marks the module as failed because its spec can't be
+// reconciled.
+func (ass *WasmModuleStatus) MarkInvalidSpec(messageFormat string, messageA ...interface{}) {
+	condSet.Manage(ass).MarkFalse(
+		WasmModuleConditionReady,
+		ReasonInvalidSpec,
+		messageFormat, messageA...)
+}
+
+// IsReady returns true if the module is ready to serve requests.
+func (ass *WasmModuleStatus) IsReady() bool {
+	return condSet.Manage(ass).IsHappy()
+}
+
+// UpdatePhase sets the phase from the Ready condition. Only the terminal
+// reasons fail the module; it's pending while any other failure may still
+// resolve, e.g. until its Service is created.
+func (ass *WasmModuleStatus) UpdatePhase() {
+	cond := condSet.Manage(ass).GetTopLevelCondition()
+	switch {
+	case cond.IsTrue():
+		ass.Phase = WasmModulePhaseReady
+	case cond.IsFalse() && isTerminalReason(cond.Reason):
+		ass.Phase = WasmModulePhaseFailed
+	default:
+		ass.Phase = WasmModulePhasePending
+	}
+}
+
+func isTerminalReason(reason string) bool {
+	return reason == ReasonProgressDeadlineExceeded || reason == ReasonInvalidSpec
 }
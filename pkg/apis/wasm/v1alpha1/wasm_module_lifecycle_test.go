@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"knative.dev/pkg/apis"
+)
+
+func TestWasmModuleConditionReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		mark     func(*WasmModuleStatus)
+		condType apis.ConditionType
+		want     string
+	}{{
+		name:     "service unavailable",
+		mark:     func(s *WasmModuleStatus) { s.MarkServiceUnavailable("strreverse") },
+		condType: WasmModuleConditionReady,
+		want:     ReasonServiceUnavailable,
+	}, {
+		name:     "no ready pods",
+		mark:     func(s *WasmModuleStatus) { s.MarkServiceNotReady(ReasonNoReadyPods, "No ready pods.") },
+		condType: WasmModuleConditionReady,
+		want:     ReasonNoReadyPods,
+	}, {
+		name:     "readiness gate not passed",
+		mark:     func(s *WasmModuleStatus) { s.MarkFunctionalNotReady("connection refused") },
+		condType: WasmModuleConditionFunctionalReady,
+		want:     ReasonReadinessGateNotPassed,
+	}, {
+		name:     "progress deadline exceeded",
+		mark:     func(s *WasmModuleStatus) { s.MarkProgressDeadlineExceeded(600) },
+		condType: WasmModuleConditionReady,
+		want:     ReasonProgressDeadlineExceeded,
+	}, {
+		name:     "invalid spec",
+		mark:     func(s *WasmModuleStatus) { s.MarkInvalidSpec("no service name") },
+		condType: WasmModuleConditionReady,
+		want:     ReasonInvalidSpec,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &WasmModuleStatus{}
+			status.InitializeConditions()
+			tc.mark(status)
+			if got := status.GetCondition(tc.condType); got == nil || got.Reason != tc.want {
+				t.Errorf("Condition %s = %+v, want reason %s", tc.condType, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWasmModulePhase(t *testing.T) {
+	tests := []struct {
+		name string
+		mark func(*WasmModuleStatus)
+		want WasmModulePhase
+	}{{
+		name: "initialized",
+		mark: func(*WasmModuleStatus) {},
+		want: WasmModulePhasePending,
+	}, {
+		name: "waiting for readiness gate",
+		mark: func(s *WasmModuleStatus) {
+			s.MarkServiceAvailable()
+			s.MarkFunctionalNotReady("connection refused")
+		},
+		want: WasmModulePhasePending,
+	}, {
+		name: "ready",
+		mark: func(s *WasmModuleStatus) {
+			s.MarkServiceAvailable()
+			s.MarkServiceReady()
+			s.MarkFunctionalReady()
+		},
+		want: WasmModulePhaseReady,
+	}, {
+		name: "pods no longer ready",
+		mark: func(s *WasmModuleStatus) {
+			s.MarkServiceAvailable()
+			s.MarkServiceReady()
+			s.MarkFunctionalReady()
+			s.MarkServiceNotReady(ReasonNoReadyPods, "No ready pods.")
+		},
+		want: WasmModulePhasePending,
+	}, {
+		name: "pods ready again",
+		mark: func(s *WasmModuleStatus) {
+			s.MarkServiceAvailable()
+			s.MarkServiceNotReady(ReasonNoReadyPods, "No ready pods.")
+			s.MarkFunctionalReady()
+			s.MarkServiceReady()
+		},
+		want: WasmModulePhaseReady,
+	}, {
+		name: "service unavailable",
+		mark: func(s *WasmModuleStatus) { s.MarkServiceUnavailable("strreverse") },
+		want: WasmModulePhasePending,
+	}, {
+		name: "invalid spec",
+		mark: func(s *WasmModuleStatus) { s.MarkInvalidSpec("No service name.") },
+		want: WasmModulePhaseFailed,
+	}, {
+		name: "progress deadline exceeded",
+		mark: func(s *WasmModuleStatus) { s.MarkProgressDeadlineExceeded(600) },
+		want: WasmModulePhaseFailed,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &WasmModuleStatus{}
+			status.InitializeConditions()
+			tc.mark(status)
+			status.UpdatePhase()
+			if status.Phase != tc.want {
+				t.Errorf("Phase = %s, want %s", status.Phase, tc.want)
+			}
+		})
+	}
+}
+
+func TestWasmModuleReadyNeedsAllDependents(t *testing.T) {
+	marks := map[apis.ConditionType]func(*WasmModuleStatus){
+		WasmModuleConditionServiceCreated:  (*WasmModuleStatus).MarkServiceAvailable,
+		WasmModuleConditionServiceReady:    (*WasmModuleStatus).MarkServiceReady,
+		WasmModuleConditionFunctionalReady: (*WasmModuleStatus).MarkFunctionalReady,
+	}
+	for skipped := range marks {
+		t.Run("without "+string(skipped), func(t *testing.T) {
+			status := &WasmModuleStatus{}
+			status.InitializeConditions()
+			for condType, mark := range marks {
+				if condType != skipped {
+					mark(status)
+				}
+			}
+			if status.IsReady() {
+				t.Errorf("Module is ready with %s unknown: %+v", skipped, status.Conditions)
+			}
+			marks[skipped](status)
+			if !status.IsReady() {
+				t.Errorf("Module isn't ready with all dependents true: %+v", status.Conditions)
+			}
+		})
+	}
+}
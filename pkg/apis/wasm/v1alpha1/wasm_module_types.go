@@ -54,13 +54,47 @@ var (
 // WasmModuleSpec holds the desired state of the WasmModule (from the client).
 type WasmModuleSpec struct {
 	// ServiceName holds the name of the Kubernetes Service to expose as an "addressable".
-	ServiceName string `json:"serviceName"`
+	// Defaults to the name of the WasmModule.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// ReadinessGate, when set, holds the WasmModule back from becoming ready
+	// until the module has served a successful request.
+	// +optional
+	ReadinessGate *ReadinessGateSpec `json:"readinessGate,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum time in seconds for the WasmModule
+	// to become ready, after which it's marked as failed with the
+	// ProgressDeadlineExceeded reason. No deadline applies when it's unset.
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// ReadinessGateSpec describes the request used to probe a WasmModule.
+type ReadinessGateSpec struct {
+	// Path is the HTTP path requested on the module. The module is considered
+	// functionally ready once it responds to it with 200 OK.
+	// +required
+	Path string `json:"path"`
 }
 
 const (
 	// WasmModuleConditionReady is set when the revision is starting to materialize
 	// runtime resources, and becomes true when those resources are ready.
 	WasmModuleConditionReady = apis.ConditionReady
+
+	// WasmModuleConditionServiceCreated is set when the Service the module
+	// is bound to has been created.
+	WasmModuleConditionServiceCreated apis.ConditionType = "ServiceCreated"
+
+	// WasmModuleConditionServiceReady is set when at least one of the pods
+	// selected by the Service is ready.
+	WasmModuleConditionServiceReady apis.ConditionType = "ServiceReady"
+
+	// WasmModuleConditionFunctionalReady is set when the module has served a
+	// successful request on the path of its readiness gate. It's true right
+	// away for modules without a readiness gate.
+	WasmModuleConditionFunctionalReady apis.ConditionType = "FunctionalReady"
 )
 
 // WasmModuleStatus communicates the observed state of the WasmModule (from the controller).
@@ -70,8 +104,52 @@ type WasmModuleStatus struct {
 	// Address holds the information needed to connect this Addressable up to receive events.
 	// +optional
 	Address *duckv1.Addressable `json:"address,omitempty"`
+
+	// ServiceName is the name of the Service the module is bound to, once it
+	// was found.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// RestartCount is the total number of container restarts of the pods
+	// selected by the Service.
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty"`
+
+	// Phase summarizes the conditions of the WasmModule.
+	// +optional
+	Phase WasmModulePhase `json:"phase,omitempty"`
+
+	// LastReconcileTime is when the controller last reconciled the WasmModule.
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// LastError is the error of the last reconcile, empty if it succeeded.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// ProgressingSince is when the WasmModule started progressing towards
+	// readiness: its creation, when it stopped being ready, or when its spec
+	// changed. The progress deadline is counted from it. It's unset while the
+	// WasmModule is ready.
+	// +optional
+	ProgressingSince *metav1.Time `json:"progressingSince,omitempty"`
 }
 
+// WasmModulePhase is a summary of the WasmModule conditions.
+type WasmModulePhase string
+
+const (
+	// WasmModulePhasePending is set while the WasmModule isn't ready yet.
+	WasmModulePhasePending WasmModulePhase = "Pending"
+
+	// WasmModulePhaseReady is set when the WasmModule is ready.
+	WasmModulePhaseReady WasmModulePhase = "Ready"
+
+	// WasmModulePhaseFailed is set when the WasmModule can't become ready
+	// without a change: its progress deadline passed, or its spec is invalid.
+	WasmModulePhaseFailed WasmModulePhase = "Failed"
+)
+
 // WasmModuleList is a list of WasmModule resources
 //
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
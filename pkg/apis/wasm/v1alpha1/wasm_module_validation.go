@@ -18,7 +18,11 @@ package v1alpha1
 
 import (
 	"context"
+	"math"
+	"net/url"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -28,9 +32,32 @@ func (as *WasmModule) Validate(ctx context.Context) *apis.FieldError {
 }
 
 // Validate implements apis.Validatable
-func (ass *WasmModuleSpec) Validate(ctx context.Context) *apis.FieldError {
+func (ass *WasmModuleSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 	if ass.ServiceName == "" {
-		return apis.ErrMissingField("serviceName")
+		errs = errs.Also(apis.ErrMissingField("serviceName"))
+	} else if msgs := validation.IsDNS1035Label(ass.ServiceName); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(ass.ServiceName, "serviceName", msgs...))
+	}
+	if ass.ReadinessGate != nil {
+		errs = errs.Also(ass.ReadinessGate.Validate(ctx).ViaField("readinessGate"))
+	}
+	if d := ass.ProgressDeadlineSeconds; d != nil && *d <= 0 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*d, 1, math.MaxInt32,
+			"progressDeadlineSeconds"))
+	}
+	return errs
+}
+
+// Validate implements apis.Validatable
+func (rgs *ReadinessGateSpec) Validate(context.Context) *apis.FieldError {
+	if rgs.Path == "" {
+		return apis.ErrMissingField("path")
+	}
+	u, err := url.Parse(rgs.Path)
+	if err != nil || !strings.HasPrefix(rgs.Path, "/") ||
+		u.Host != "" || u.RawQuery != "" || u.Fragment != "" {
+		return apis.ErrInvalidValue(rgs.Path, "path",
+			"must be an absolute URL path, without a query or fragment")
 	}
 	return nil
 }
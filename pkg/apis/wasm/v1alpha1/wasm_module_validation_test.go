@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+func TestWasmModuleValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		spec WasmModuleSpec
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: WasmModuleSpec{ServiceName: "strreverse"},
+	}, {
+		name: "missing service name",
+		spec: WasmModuleSpec{},
+		want: apis.ErrMissingField("spec.serviceName"),
+	}, {
+		name: "uppercase service name",
+		spec: WasmModuleSpec{ServiceName: "StrReverse"},
+		want: apis.ErrInvalidValue("StrReverse", "spec.serviceName",
+			validation.IsDNS1035Label("StrReverse")...),
+	}, {
+		name: "service name with underscore",
+		spec: WasmModuleSpec{ServiceName: "str_reverse"},
+		want: apis.ErrInvalidValue("str_reverse", "spec.serviceName",
+			validation.IsDNS1035Label("str_reverse")...),
+	}, {
+		name: "service name with leading digit",
+		spec: WasmModuleSpec{ServiceName: "1-strreverse"},
+		want: apis.ErrInvalidValue("1-strreverse", "spec.serviceName",
+			validation.IsDNS1035Label("1-strreverse")...),
+	}, {
+		name: "too long service name",
+		spec: WasmModuleSpec{ServiceName: strings.Repeat("s", 64)},
+		want: apis.ErrInvalidValue(strings.Repeat("s", 64), "spec.serviceName",
+			validation.IsDNS1035Label(strings.Repeat("s", 64))...),
+	}, {
+		name: "valid readiness gate",
+		spec: WasmModuleSpec{
+			ServiceName:   "strreverse",
+			ReadinessGate: &ReadinessGateSpec{Path: "/healthz"},
+		},
+	}, {
+		name: "readiness gate without path",
+		spec: WasmModuleSpec{
+			ServiceName:   "strreverse",
+			ReadinessGate: &ReadinessGateSpec{},
+		},
+		want: apis.ErrMissingField("spec.readinessGate.path"),
+	}, {
+		name: "relative readiness gate path",
+		spec: WasmModuleSpec{
+			ServiceName:   "strreverse",
+			ReadinessGate: &ReadinessGateSpec{Path: "healthz"},
+		},
+		want: apis.ErrInvalidValue("healthz", "spec.readinessGate.path",
+			"must be an absolute URL path, without a query or fragment"),
+	}, {
+		name: "readiness gate path with query",
+		spec: WasmModuleSpec{
+			ServiceName:   "strreverse",
+			ReadinessGate: &ReadinessGateSpec{Path: "/?text=abc"},
+		},
+		want: apis.ErrInvalidValue("/?text=abc", "spec.readinessGate.path",
+			"must be an absolute URL path, without a query or fragment"),
+	}, {
+		name: "valid progress deadline",
+		spec: WasmModuleSpec{
+			ServiceName:             "strreverse",
+			ProgressDeadlineSeconds: ptr.Int32(600),
+		},
+	}, {
+		name: "zero progress deadline",
+		spec: WasmModuleSpec{
+			ServiceName:             "strreverse",
+			ProgressDeadlineSeconds: ptr.Int32(0),
+		},
+		want: apis.ErrOutOfBoundsValue(0, 1, math.MaxInt32,
+			"spec.progressDeadlineSeconds"),
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			module := &WasmModule{Spec: tc.spec}
+			got := module.Validate(context.Background())
+			if got.Error() != tc.want.Error() {
+				t.Errorf("Validate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
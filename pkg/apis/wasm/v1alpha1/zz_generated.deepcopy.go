@@ -26,12 +26,28 @@ import (
 	v1 "knative.dev/pkg/apis/duck/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessGateSpec) DeepCopyInto(out *ReadinessGateSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessGateSpec.
+func (in *ReadinessGateSpec) DeepCopy() *ReadinessGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WasmModule) DeepCopyInto(out *WasmModule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -90,6 +106,16 @@ func (in *WasmModuleList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WasmModuleSpec) DeepCopyInto(out *WasmModuleSpec) {
 	*out = *in
+	if in.ReadinessGate != nil {
+		in, out := &in.ReadinessGate, &out.ReadinessGate
+		*out = new(ReadinessGateSpec)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -112,6 +138,14 @@ func (in *WasmModuleStatus) DeepCopyInto(out *WasmModuleStatus) {
 		*out = new(v1.Addressable)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ProgressingSince != nil {
+		in, out := &in.ProgressingSince, &out.ProgressingSince
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -17,11 +17,3 @@ limitations under the License.
 // Code generated by lister-gen. DO NOT EDIT.
 
 package v1alpha1
-
-// WasmModuleListerExpansion allows custom methods to be added to
-// WasmModuleLister.
-type WasmModuleListerExpansion interface{}
-
-// WasmModuleNamespaceListerExpansion allows custom methods to be added to
-// WasmModuleNamespaceLister.
-type WasmModuleNamespaceListerExpansion interface{}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	v1alpha1 "github.com/cardil/knative-serving-wasm/pkg/apis/wasm/v1alpha1"
+)
+
+// ServiceNameIndex is the name of the index of WasmModules by the Service
+// they are bound to. It must be registered with ServiceNameIndexFunc on the
+// informer backing the lister.
+const ServiceNameIndex = "serviceName"
+
+// ServiceNameIndexFunc indexes WasmModules by the namespace and the name of
+// the Service they are bound to.
+func ServiceNameIndexFunc(obj interface{}) ([]string, error) {
+	module, ok := obj.(*v1alpha1.WasmModule)
+	if !ok {
+		return nil, fmt.Errorf("expected a WasmModule, got %T", obj)
+	}
+	name := module.EffectiveServiceName()
+	if name == "" {
+		return nil, nil
+	}
+	return []string{serviceNameKey(module.Namespace, name)}, nil
+}
+
+func serviceNameKey(namespace, serviceName string) string {
+	return namespace + "/" + serviceName
+}
+
+// WasmModuleListerExpansion allows custom methods to be added to
+// WasmModuleLister.
+type WasmModuleListerExpansion interface {
+	// WasmModulesForService lists the WasmModules bound to the given Service,
+	// using the ServiceNameIndex.
+	// Objects returned here must be treated as read-only.
+	WasmModulesForService(namespace, serviceName string) ([]*v1alpha1.WasmModule, error)
+}
+
+// WasmModuleNamespaceListerExpansion allows custom methods to be added to
+// WasmModuleNamespaceLister.
+type WasmModuleNamespaceListerExpansion interface{}
+
+// WasmModulesForService implements WasmModuleListerExpansion.
+func (s *wasmModuleLister) WasmModulesForService(namespace, serviceName string) ([]*v1alpha1.WasmModule, error) {
+	objs, err := s.indexer.ByIndex(ServiceNameIndex, serviceNameKey(namespace, serviceName))
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*v1alpha1.WasmModule, 0, len(objs))
+	for _, obj := range objs {
+		ret = append(ret, obj.(*v1alpha1.WasmModule))
+	}
+	return ret, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sort"
+	"testing"
+
+	v1alpha1 "github.com/cardil/knative-serving-wasm/pkg/apis/wasm/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestWasmModulesForService(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		ServiceNameIndex:     ServiceNameIndexFunc,
+	})
+	for _, module := range []*v1alpha1.WasmModule{
+		newModule("default", "reverse-text", "strreverse"),
+		newModule("default", "reverse-again", "strreverse"),
+		newModule("default", "uppercase", "strupper"),
+		newModule("other", "reverse-text", "strreverse"),
+		// Bound to the Service of its own name.
+		newModule("default", "strreverse", ""),
+	} {
+		if err := indexer.Add(module); err != nil {
+			t.Fatal("Error adding module to indexer:", err)
+		}
+	}
+
+	modules, err := NewWasmModuleLister(indexer).WasmModulesForService("default", "strreverse")
+	if err != nil {
+		t.Fatal("WasmModulesForService() =", err)
+	}
+	var got []string
+	for _, module := range modules {
+		got = append(got, module.Namespace+"/"+module.Name)
+	}
+	sort.Strings(got)
+	want := []string{"default/reverse-again", "default/reverse-text", "default/strreverse"}
+	if len(got) != len(want) {
+		t.Fatalf("WasmModulesForService() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("WasmModulesForService() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func newModule(namespace, name, serviceName string) *v1alpha1.WasmModule {
+	return &v1alpha1.WasmModule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1alpha1.WasmModuleSpec{ServiceName: serviceName},
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	synced := false
+	handler := Handler(
+		func() bool { return true },
+		func() bool { return synced },
+	)
+
+	tests := []struct {
+		path   string
+		synced bool
+		want   int
+	}{
+		{path: "/healthz", synced: false, want: http.StatusOK},
+		{path: "/readyz", synced: false, want: http.StatusServiceUnavailable},
+		{path: "/healthz", synced: true, want: http.StatusOK},
+		{path: "/readyz", synced: true, want: http.StatusOK},
+	}
+	for _, tc := range tests {
+		synced = tc.synced
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.want {
+			t.Errorf("GET %s with synced=%v = %d, want %d", tc.path, tc.synced, rec.Code, tc.want)
+		}
+	}
+}
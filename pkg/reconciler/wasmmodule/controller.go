@@ -18,16 +18,36 @@ package wasmmodule
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
 
+	"go.opencensus.io/stats/view"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
 
 	wasmmoduleinformer "github.com/cardil/knative-serving-wasm/pkg/client/injection/informers/wasm/v1alpha1/wasmmodule"
 	wasmmodulereconciler "github.com/cardil/knative-serving-wasm/pkg/client/injection/reconciler/wasm/v1alpha1/wasmmodule"
+	wasmlisters "github.com/cardil/knative-serving-wasm/pkg/client/listers/wasm/v1alpha1"
+	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
 	svcinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 )
 
+const (
+	// WorkersEnv is the name of the environment variable holding the number
+	// of WasmModules reconciled concurrently. It defaults to
+	// controller.DefaultThreadsPerController.
+	WorkersEnv = "RECONCILE_WORKERS"
+
+	// maxWorkers bounds WorkersEnv. Reconciles mostly wait on listers and
+	// readiness probes, so more workers than this only add contention.
+	maxWorkers = 64
+)
+
 // NewController creates a Reconciler and returns the result of NewImpl.
 func NewController(
 	ctx context.Context,
@@ -35,13 +55,38 @@ func NewController(
 ) *controller.Impl {
 	wasmmoduleInformer := wasmmoduleinformer.Get(ctx)
 	svcInformer := svcinformer.Get(ctx)
+	podInformer := podinformer.Get(ctx)
 
+	clk := clock.RealClock{}
+	notifier, err := NewReadyNotifier(os.Getenv(ReadyEventSinkEnv), clk)
+	if err != nil {
+		logging.FromContext(ctx).Fatal("Error creating the ready event client: ", err)
+	}
 	r := &Reconciler{
 		ServiceLister: svcInformer.Lister(),
+		PodLister:     podInformer.Lister(),
+		Clock:         clk,
+		Prober:        NewHTTPProber(),
+		ReadyNotifier: notifier,
 	}
 	impl := wasmmodulereconciler.NewImpl(ctx, r)
 	r.Tracker = impl.Tracker
 
+	if err := view.Register(views...); err != nil {
+		logging.FromContext(ctx).Fatal("Error registering reconcile metrics: ", err)
+	}
+
+	workers, err := parseWorkers(os.Getenv(WorkersEnv))
+	if err != nil {
+		logging.FromContext(ctx).Fatal("Error reading worker count: ", err)
+	}
+	impl.Concurrency = workers
+
+	if err := wasmmoduleInformer.Informer().AddIndexers(cache.Indexers{
+		wasmlisters.ServiceNameIndex: wasmlisters.ServiceNameIndexFunc,
+	}); err != nil {
+		logging.FromContext(ctx).Fatal("Error adding the service name index: ", err)
+	}
 	wasmmoduleInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
 	svcInformer.Informer().AddEventHandler(controller.HandleAll(
@@ -54,5 +99,27 @@ func NewController(
 		),
 	))
 
+	podInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(
+			r.Tracker.OnChanged,
+			corev1.SchemeGroupVersion.WithKind("Pod"),
+		),
+	))
+
 	return impl
 }
+
+func parseWorkers(value string) (int, error) {
+	if value == "" {
+		return controller.DefaultThreadsPerController, nil
+	}
+	workers, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", WorkersEnv, value, err)
+	}
+	if workers < 1 || workers > maxWorkers {
+		return 0, fmt.Errorf("%s must be between 1 and %d, got %d",
+			WorkersEnv, maxWorkers, workers)
+	}
+	return workers, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasmmodule
+
+import (
+	"testing"
+
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	reconcilertesting "knative.dev/pkg/reconciler/testing"
+
+	wasmmoduleinformer "github.com/cardil/knative-serving-wasm/pkg/client/injection/informers/wasm/v1alpha1/wasmmodule"
+	_ "github.com/cardil/knative-serving-wasm/pkg/client/injection/informers/wasm/v1alpha1/wasmmodule/fake"
+	wasmlisters "github.com/cardil/knative-serving-wasm/pkg/client/listers/wasm/v1alpha1"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/service/fake"
+)
+
+func TestNewController(t *testing.T) {
+	t.Setenv(WorkersEnv, "8")
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+	impl := NewController(ctx, configmap.NewStaticWatcher())
+	if impl == nil {
+		t.Fatal("NewController() = nil")
+	}
+	if impl.Concurrency != 8 {
+		t.Errorf("Concurrency = %d, want 8", impl.Concurrency)
+	}
+	indexers := wasmmoduleinformer.Get(ctx).Informer().GetIndexer().GetIndexers()
+	if _, ok := indexers[wasmlisters.ServiceNameIndex]; !ok {
+		t.Errorf("Indexers = %v, want %s registered", indexers, wasmlisters.ServiceNameIndex)
+	}
+}
+
+func TestParseWorkers(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "", want: controller.DefaultThreadsPerController},
+		{value: "1", want: 1},
+		{value: "64", want: 64},
+		{value: "0", wantErr: true},
+		{value: "65", wantErr: true},
+		{value: "many", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := parseWorkers(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseWorkers(%q) = %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseWorkers(%q) = %d, want %d", tc.value, got, tc.want)
+			}
+		})
+	}
+}
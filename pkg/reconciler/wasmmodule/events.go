@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasmmodule
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"k8s.io/utils/clock"
+
+	api "github.com/cardil/knative-serving-wasm/pkg/apis/wasm/v1alpha1"
+)
+
+const (
+	// ReadyEventSinkEnv is the name of the environment variable holding the
+	// URL to which a CloudEvent is sent when a WasmModule becomes Ready.
+	// When it's empty, no events are sent.
+	ReadyEventSinkEnv = "READY_EVENT_SINK"
+
+	// ReadyEventType is the CloudEvent type sent on the Ready transition.
+	ReadyEventType = "dev.knative.serving.wasm.module.ready"
+)
+
+// ReadyNotifier is notified when a WasmModule transitions to Ready.
+type ReadyNotifier interface {
+	NotifyReady(ctx context.Context, module *api.WasmModule) error
+}
+
+// ReadyEventData is the payload of the ReadyEventType CloudEvent.
+type ReadyEventData struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	URL       string `json:"url,omitempty"`
+}
+
+// NewReadyNotifier returns a ReadyNotifier that sends binary-mode CloudEvents
+// to the given sink, or nil if the sink is empty. The events are stamped with
+// the time of the given clock.
+func NewReadyNotifier(sink string, clk clock.PassiveClock) (ReadyNotifier, error) {
+	if sink == "" {
+		return nil, nil
+	}
+	client, err := cloudevents.NewClientHTTP(
+		cloudevents.WithTarget(sink),
+		cehttp.WithClient(http.Client{Timeout: 10 * time.Second}))
+	if err != nil {
+		return nil, err
+	}
+	return &cloudEventNotifier{client: client, clock: clk}, nil
+}
+
+type cloudEventNotifier struct {
+	client cloudevents.Client
+	clock  clock.PassiveClock
+}
+
+// NotifyReady implements ReadyNotifier.
+func (n *cloudEventNotifier) NotifyReady(ctx context.Context, module *api.WasmModule) error {
+	data := ReadyEventData{
+		Name:      module.Name,
+		Namespace: module.Namespace,
+	}
+	if addr := module.Status.Address; addr != nil && addr.URL != nil {
+		data.URL = addr.URL.String()
+	}
+
+	// The client sets a random ID.
+	event := cloudevents.NewEvent()
+	event.SetType(ReadyEventType)
+	event.SetSource(fmt.Sprintf("/apis/%s/namespaces/%s/wasmmodules/%s",
+		api.SchemeGroupVersion.String(), module.Namespace, module.Name))
+	event.SetSubject(module.Name)
+	event.SetTime(n.clock.Now().UTC())
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return err
+	}
+
+	if result := n.client.Send(ctx, event); !cloudevents.IsACK(result) {
+		return fmt.Errorf("sending %s event: %w", ReadyEventType, result)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasmmodule
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	api "github.com/cardil/knative-serving-wasm/pkg/apis/wasm/v1alpha1"
+)
+
+// Results of a reconcile, as tagged on the reconcile metrics.
+const (
+	resultSuccess = "success"
+	resultRequeue = "requeue"
+	resultError   = "error"
+)
+
+var (
+	// The generic reconcile_count and reconcile_latency of the controller
+	// report requeues as failures, since they only see a non-nil error.
+	// These measures tell requeues apart from actual reconcile errors.
+	reconcileCountM = stats.Int64(
+		"wasmmodule_reconcile_count",
+		"Number of WasmModule reconciles",
+		stats.UnitDimensionless)
+	reconcileLatencyM = stats.Float64(
+		"wasmmodule_reconcile_latency",
+		"Latency of WasmModule reconciles",
+		stats.UnitMilliseconds)
+
+	resultTagKey = tag.MustNewKey("result")
+
+	// views are registered by NewController. Failed reconciles are counted
+	// with the result tag set to "error".
+	views = []*view.View{{
+		Description: reconcileCountM.Description(),
+		Measure:     reconcileCountM,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{resultTagKey, controller.NamespaceTagKey},
+	}, {
+		Description: reconcileLatencyM.Description(),
+		Measure:     reconcileLatencyM,
+		// Bucket boundaries are 10ms, 100ms, 1s, 10s, 30s and 60s.
+		Aggregation: view.Distribution(10, 100, 1000, 10000, 30000, 60000),
+		TagKeys:     []tag.Key{resultTagKey, controller.NamespaceTagKey},
+	}}
+)
+
+// reconcileResult classifies the event returned by a reconcile.
+func reconcileResult(event reconciler.Event) string {
+	if event == nil {
+		return resultSuccess
+	}
+	if requeue, _ := controller.IsRequeueKey(event); requeue {
+		return resultRequeue
+	}
+	return resultError
+}
+
+// reportReconcile records the outcome and the latency of a reconcile that
+// started at the given time.
+func (r *Reconciler) reportReconcile(ctx context.Context, o *api.WasmModule, start time.Time, event reconciler.Event) {
+	ctx, err := tag.New(ctx,
+		tag.Upsert(resultTagKey, reconcileResult(event)),
+		tag.Upsert(controller.NamespaceTagKey, o.Namespace))
+	if err != nil {
+		logging.FromContext(ctx).Warn("Error tagging reconcile metrics: ", err)
+		return
+	}
+	latency := float64(r.Clock.Since(start)) / float64(time.Millisecond)
+	stats.Record(ctx, reconcileCountM.M(1), reconcileLatencyM.M(latency))
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasmmodule
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Prober checks whether a module serves the given URL successfully.
+type Prober interface {
+	// Probe returns nil if the target responded with 200 OK.
+	Probe(ctx context.Context, target string) error
+}
+
+// NewHTTPProber returns a Prober issuing plain HTTP GET requests.
+func NewHTTPProber() Prober {
+	return &httpProber{
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type httpProber struct {
+	client *http.Client
+}
+
+// Probe implements Prober.
+func (p *httpProber) Probe(ctx context.Context, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with %s", target, resp.Status)
+	}
+	return nil
+}
@@ -18,14 +18,21 @@ package wasmmodule
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/clock"
 
 	api "github.com/cardil/knative-serving-wasm/pkg/apis/wasm/v1alpha1"
 	apireconciler "github.com/cardil/knative-serving-wasm/pkg/client/injection/reconciler/wasm/v1alpha1/wasmmodule"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/network"
 	"knative.dev/pkg/reconciler"
@@ -41,41 +48,275 @@ type Reconciler struct {
 
 	// Listers index properties about resources
 	ServiceLister corev1listers.ServiceLister
+	PodLister     corev1listers.PodLister
+
+	// Clock is used to tell the current time.
+	Clock clock.PassiveClock
+
+	// Prober checks the readiness gates of WasmModules.
+	Prober Prober
+
+	// ReadyNotifier, when set, is notified each time a WasmModule
+	// transitions to Ready, once the transition is stored.
+	ReadyNotifier ReadyNotifier
 }
 
+// readyEventPendingAnnotation is the status annotation set on a module that
+// became ready, until the event announcing it is sent.
+const readyEventPendingAnnotation = "wasm.serving.knative.dev/ready-event-pending"
+
+// readinessGateRetryPeriod is how long to wait before probing a module's
+// readiness gate again.
+const readinessGateRetryPeriod = 5 * time.Second
+
 // Check that our Reconciler implements Interface
 var _ apireconciler.Interface = (*Reconciler)(nil)
 
 // ReconcileKind implements Interface.ReconcileKind.
-func (r *Reconciler) ReconcileKind(ctx context.Context, o *api.WasmModule) reconciler.Event {
+func (r *Reconciler) ReconcileKind(ctx context.Context, o *api.WasmModule) (event reconciler.Event) {
 	logger := logging.FromContext(ctx)
+	if !o.DeletionTimestamp.IsZero() {
+		// The generated reconciler doesn't call us for deleted modules, but
+		// make sure nothing is tracked or probed while garbage collection
+		// tears the module down.
+		logger.Debug("WasmModule is being deleted: ", o.Name)
+		return nil
+	}
+	start := r.Clock.Now()
+	defer func() {
+		o.Status.UpdatePhase()
+		r.recordReconcile(o, event)
+		r.reportReconcile(ctx, o, start, event)
+	}()
+
+	if o.EffectiveServiceName() == "" {
+		// Creating or tracking a Service without a name fails obscurely, so
+		// report the problem on the module instead. Retrying won't help.
+		o.Status.MarkInvalidSpec(
+			"No service name: spec.serviceName is empty and %q isn't a valid service name.", o.Name)
+		return nil
+	}
+
+	wasReady := o.Status.IsReady()
+
+	event = r.reconcileModule(ctx, o)
+
+	if !o.Status.IsReady() {
+		setReadyEventPending(o, false)
+		return r.checkProgressDeadline(o, r.progressStart(o, wasReady), event)
+	}
+	o.Status.ProgressingSince = nil
+	r.notifyReady(ctx, o, wasReady)
+	return event
+}
+
+// notifyReady sends the ready event of a module that is ready. The module was
+// passed in with its stored status, so when it wasn't ready, the event is only
+// marked pending. It's sent by the reconcile that follows the status update,
+// so a failed update doesn't send the event twice.
+func (r *Reconciler) notifyReady(ctx context.Context, o *api.WasmModule, wasReady bool) {
+	if r.ReadyNotifier == nil {
+		return
+	}
+	if !wasReady {
+		setReadyEventPending(o, true)
+		return
+	}
+	if o.Status.Annotations[readyEventPendingAnnotation] != "true" {
+		return
+	}
+	if err := r.ReadyNotifier.NotifyReady(ctx, o); err != nil {
+		// The event stays pending, so it's retried by the next reconcile.
+		logging.FromContext(ctx).Warnf("Error sending ready event for %s: %v", o.Name, err)
+		return
+	}
+	setReadyEventPending(o, false)
+}
+
+// setReadyEventPending marks whether the ready event of a module is still to
+// be sent. The mark is reconciler state, so it's kept in the status
+// annotations rather than in the API.
+func setReadyEventPending(o *api.WasmModule, pending bool) {
+	if !pending {
+		delete(o.Status.Annotations, readyEventPendingAnnotation)
+		return
+	}
+	if o.Status.Annotations == nil {
+		o.Status.Annotations = make(map[string]string, 1)
+	}
+	o.Status.Annotations[readyEventPendingAnnotation] = "true"
+}
+
+// recordReconcile stamps the module status with the time and the outcome of
+// the reconcile; requeues aren't errors. The time is truncated to the second
+// precision it's stored with, so the reconcile triggered by the resulting
+// status update doesn't update it again.
+func (r *Reconciler) recordReconcile(o *api.WasmModule, event reconciler.Event) {
+	now := metav1.NewTime(r.Clock.Now().Truncate(time.Second))
+	o.Status.LastReconcileTime = &now
+	o.Status.LastError = ""
+	if reconcileResult(event) == resultError {
+		o.Status.LastError = event.Error()
+	}
+}
+
+func (r *Reconciler) reconcileModule(ctx context.Context, o *api.WasmModule) reconciler.Event {
+	logger := logging.FromContext(ctx)
+	name := o.EffectiveServiceName()
 
 	if err := r.Tracker.TrackReference(tracker.Reference{
 		APIVersion: "v1",
 		Kind:       "Service",
-		Name:       o.Spec.ServiceName,
+		Name:       name,
 		Namespace:  o.Namespace,
 	}, o); err != nil {
-		logger.Errorf("Error tracking service %s: %v", o.Spec.ServiceName, err)
+		logger.Errorf("Error tracking service %s: %v", name, err)
 		return err
 	}
 
-	if _, err := r.ServiceLister.Services(o.Namespace).Get(o.Spec.ServiceName); apierrs.IsNotFound(err) {
-		logger.Info("Service does not yet exist:", o.Spec.ServiceName)
-		o.Status.MarkServiceUnavailable(o.Spec.ServiceName)
+	svc, err := r.ServiceLister.Services(o.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		logger.Info("Service does not yet exist:", name)
+		o.Status.ServiceName = ""
+		o.Status.MarkServiceUnavailable(name)
 		return nil
 	} else if err != nil {
-		logger.Errorf("Error reconciling service %s: %v", o.Spec.ServiceName, err)
+		logger.Errorf("Error reconciling service %s: %v", name, err)
+		return err
+	}
+	o.Status.ServiceName = svc.Name
+
+	hasReadyPods, err := r.reconcilePods(o, svc)
+	if err != nil {
+		logger.Errorf("Error listing pods of service %s: %v", name, err)
 		return err
 	}
 
-	o.Status.MarkServiceAvailable()
 	o.Status.Address = &duckv1.Addressable{
 		URL: &apis.URL{
 			Scheme: "http",
-			Host:   network.GetServiceHostname(o.Spec.ServiceName, o.Namespace),
+			Host:   network.GetServiceHostname(name, o.Namespace),
 		},
 	}
+	o.Status.MarkServiceAvailable()
+	if !hasReadyPods {
+		o.Status.MarkServiceNotReady(api.ReasonNoReadyPods,
+			fmt.Sprintf("None of the pods selected by service %q is ready.", name))
+		return nil
+	}
+	o.Status.MarkServiceReady()
+
+	return r.reconcileReadinessGate(ctx, o)
+}
+
+// reconcilePods sums the container restarts of the pods selected by the
+// module's Service into its status, and tells whether any of them is ready.
+func (r *Reconciler) reconcilePods(o *api.WasmModule, svc *corev1.Service) (bool, error) {
+	o.Status.RestartCount = 0
+	if len(svc.Spec.Selector) == 0 {
+		// The endpoints of Services without a selector are managed by hand,
+		// so there are no pods to tell their readiness by.
+		return true, nil
+	}
+
+	if err := r.Tracker.TrackReference(tracker.Reference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  o.Namespace,
+		Selector:   &metav1.LabelSelector{MatchLabels: svc.Spec.Selector},
+	}, o); err != nil {
+		return false, err
+	}
+
+	pods, err := r.PodLister.Pods(o.Namespace).List(labels.SelectorFromSet(svc.Spec.Selector))
+	if err != nil {
+		return false, err
+	}
+	hasReadyPods := false
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			o.Status.RestartCount += cs.RestartCount
+		}
+		hasReadyPods = hasReadyPods || isPodReady(pod)
+	}
+	return hasReadyPods, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// progressStart returns the time since which the module, which isn't ready,
+// is expected to make progress towards readiness: its creation, when it
+// stopped being ready, or when its spec changed. The time is kept in the
+// status, as the transitions of the Ready condition also follow changes of its
+// reason and message.
+func (r *Reconciler) progressStart(o *api.WasmModule, wasReady bool) time.Time {
+	// Each new generation of the spec gets the whole deadline.
+	newGeneration := o.Status.ObservedGeneration != o.Generation
+	if o.Status.ProgressingSince == nil || newGeneration {
+		since := o.CreationTimestamp
+		if wasReady || since.IsZero() || o.Status.ObservedGeneration != 0 {
+			since = metav1.NewTime(r.Clock.Now().Truncate(time.Second))
+		}
+		o.Status.ProgressingSince = &since
+	}
+	return o.Status.ProgressingSince.Time
+}
 
+// checkProgressDeadline marks a module, which isn't ready, as failed once its
+// progress deadline passes. Until then, the module is requeued so the deadline
+// is checked even if nothing else changes. Past the deadline, the requeue the
+// module asked for is kept, so a readiness gate is still probed and the module
+// can still become ready.
+func (r *Reconciler) checkProgressDeadline(o *api.WasmModule, since time.Time, event reconciler.Event) reconciler.Event {
+	requeue, after := controller.IsRequeueKey(event)
+	deadline := o.Spec.ProgressDeadlineSeconds
+	if deadline == nil || (event != nil && !requeue) {
+		return event
+	}
+
+	remaining := since.Add(time.Duration(*deadline) * time.Second).Sub(r.Clock.Now())
+	if remaining <= 0 {
+		o.Status.MarkProgressDeadlineExceeded(*deadline)
+		return event
+	}
+	if requeue {
+		remaining = min(remaining, after)
+	}
+	return controller.NewRequeueAfter(remaining)
+}
+
+// reconcileReadinessGate probes the module within the reconcile loop, so its
+// outcome reaches the API server through the generated reconciler's status
+// update, which retries on conflicts. The probe is synchronous: it holds a
+// reconcile worker for up to the prober's timeout, so no more modules than
+// there are workers (see WorkersEnv) are probed at once.
+func (r *Reconciler) reconcileReadinessGate(ctx context.Context, o *api.WasmModule) reconciler.Event {
+	gate := o.Spec.ReadinessGate
+	if gate == nil {
+		o.Status.MarkFunctionalReady()
+		return nil
+	}
+	if o.Status.GetCondition(api.WasmModuleConditionFunctionalReady).IsTrue() &&
+		o.Status.ObservedGeneration == o.Generation {
+		// The gate only needs to pass once for each generation of the spec,
+		// so a gate that's added or changed later is probed too.
+		return nil
+	}
+
+	target := *o.Status.Address.URL
+	target.Path = gate.Path
+	if err := r.Prober.Probe(ctx, target.String()); err != nil {
+		logging.FromContext(ctx).Debugf("Readiness gate of %s not passed: %v", o.Name, err)
+		o.Status.MarkFunctionalNotReady("Probing %s failed: %v", target.String(), err)
+		return controller.NewRequeueAfter(readinessGateRetryPeriod)
+	}
+	o.Status.MarkFunctionalReady()
 	return nil
 }
@@ -0,0 +1,719 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasmmodule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opencensus.io/stats/view"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricstest"
+	"knative.dev/pkg/ptr"
+	"knative.dev/pkg/reconciler"
+	reconcilertesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/tracker"
+
+	api "github.com/cardil/knative-serving-wasm/pkg/apis/wasm/v1alpha1"
+	fakeclientset "github.com/cardil/knative-serving-wasm/pkg/client/clientset/versioned/fake"
+	apireconciler "github.com/cardil/knative-serving-wasm/pkg/client/injection/reconciler/wasm/v1alpha1/wasmmodule"
+	wasmlisters "github.com/cardil/knative-serving-wasm/pkg/client/listers/wasm/v1alpha1"
+)
+
+const (
+	testNamespace   = "default"
+	testModuleName  = "reverse-text"
+	testServiceName = "strreverse"
+)
+
+func TestReconcileSendsReadyEventOnce(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []ReadyEventData
+	)
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if got := req.Header.Get("Ce-Type"); got != ReadyEventType {
+			t.Errorf("Ce-Type = %q, want %q", got, ReadyEventType)
+		}
+		if got, want := req.Header.Get("Ce-Time"), now.Format(time.RFC3339); got != want {
+			t.Errorf("Ce-Time = %q, want %q", got, want)
+		}
+		var data ReadyEventData
+		if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+			t.Errorf("Error decoding event data: %v", err)
+		}
+		mu.Lock()
+		events = append(events, data)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	r := newTestReconciler(t, newService(), newRunnerPod())
+	r.Clock = clocktesting.NewFakeClock(now)
+	notifier, err := NewReadyNotifier(sink.URL, r.Clock)
+	if err != nil {
+		t.Fatal("NewReadyNotifier() =", err)
+	}
+	r.ReadyNotifier = notifier
+	module := newModule()
+
+	// The status of this reconcile is never stored, e.g. the update failed, so
+	// the module is reconciled again with its old status.
+	if err := r.ReconcileKind(context.Background(), module.DeepCopy()); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := r.ReconcileKind(context.Background(), module); err != nil {
+			t.Fatalf("ReconcileKind() = %v", err)
+		}
+		if got := module.Status.Annotations[readyEventPendingAnnotation]; i == 0 && got != "true" {
+			t.Errorf("Annotation %s = %q, want true until the status is stored", readyEventPendingAnnotation, got)
+		}
+	}
+	if got, ok := module.Status.Annotations[readyEventPendingAnnotation]; ok {
+		t.Errorf("Annotation %s = %q, want it removed once the event is sent", readyEventPendingAnnotation, got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 {
+		t.Fatalf("Got %d events, want 1", len(events))
+	}
+	want := ReadyEventData{
+		Name:      testModuleName,
+		Namespace: testNamespace,
+		URL:       "http://" + testServiceName + "." + testNamespace + ".svc.cluster.local",
+	}
+	if events[0] != want {
+		t.Errorf("Event data = %+v, want %+v", events[0], want)
+	}
+}
+
+func TestReadyNotifierRejected(t *testing.T) {
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer sink.Close()
+
+	notifier, err := NewReadyNotifier(sink.URL, clock.RealClock{})
+	if err != nil {
+		t.Fatal("NewReadyNotifier() =", err)
+	}
+	if err := notifier.NotifyReady(context.Background(), newModule()); err == nil {
+		t.Error("NotifyReady() = nil, want an error")
+	}
+}
+
+func TestNewReadyNotifierWithoutSink(t *testing.T) {
+	if n, err := NewReadyNotifier("", clock.RealClock{}); n != nil || err != nil {
+		t.Errorf("NewReadyNotifier(\"\") = %v, %v, want nil", n, err)
+	}
+}
+
+func TestReconcileReadinessGate(t *testing.T) {
+	prober := &fakeProber{err: errors.New("connection refused")}
+	r := newTestReconciler(t, newService(), newRunnerPod())
+	r.Prober = prober
+	module := newModule()
+	module.Spec.ReadinessGate = &api.ReadinessGateSpec{Path: "/healthz"}
+	module.Status.InitializeConditions()
+
+	err := r.ReconcileKind(context.Background(), module)
+	if ok, _ := controller.IsRequeueKey(err); !ok {
+		t.Fatalf("ReconcileKind() = %v, want a requeue", err)
+	}
+	if module.Status.IsReady() {
+		t.Error("Module is ready before its readiness gate passed")
+	}
+	cond := module.Status.GetCondition(api.WasmModuleConditionFunctionalReady)
+	if !cond.IsUnknown() {
+		t.Errorf("FunctionalReady = %+v, want Unknown", cond)
+	}
+
+	prober.err = nil
+	if err = r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if !module.Status.IsReady() {
+		t.Errorf("Module isn't ready after its readiness gate passed: %+v",
+			module.Status.Conditions)
+	}
+	want := "http://" + testServiceName + "." + testNamespace + ".svc.cluster.local/healthz"
+	if len(prober.targets) != 2 || prober.targets[1] != want {
+		t.Errorf("Probed targets = %v, want 2 probes of %s", prober.targets, want)
+	}
+
+	if err = r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if len(prober.targets) != 2 {
+		t.Errorf("Probed %d times, want no probes after the gate passed",
+			len(prober.targets))
+	}
+}
+
+func TestReconcileReadinessGateAdded(t *testing.T) {
+	prober := &fakeProber{err: errors.New("connection refused")}
+	r := newTestReconciler(t, newService(), newRunnerPod())
+	r.Prober = prober
+	module := newModule()
+	module.Generation = 1
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if !module.Status.IsReady() {
+		t.Fatalf("Module isn't ready: %+v", module.Status.Conditions)
+	}
+
+	// A readiness gate is added to the ready module. The generated reconciler
+	// has stored the generation it observed.
+	module.Status.ObservedGeneration = module.Generation
+	module.Generation++
+	module.Spec.ReadinessGate = &api.ReadinessGateSpec{Path: "/healthz"}
+
+	err := r.ReconcileKind(context.Background(), module)
+	if ok, _ := controller.IsRequeueKey(err); !ok {
+		t.Fatalf("ReconcileKind() = %v, want a requeue", err)
+	}
+	if module.Status.IsReady() {
+		t.Error("Module is ready before its new readiness gate passed")
+	}
+	if cond := module.Status.GetCondition(api.WasmModuleConditionFunctionalReady); cond.IsTrue() {
+		t.Errorf("FunctionalReady = %+v, want not True", cond)
+	}
+
+	// The generated reconciler stores the new generation, even though the
+	// gate didn't pass, so it's probed by the following reconciles too.
+	module.Status.ObservedGeneration = module.Generation
+	prober.err = nil
+	if err = r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if !module.Status.IsReady() {
+		t.Errorf("Module isn't ready after its readiness gate passed: %+v",
+			module.Status.Conditions)
+	}
+	if len(prober.targets) != 2 {
+		t.Errorf("Probed %d times, want 2", len(prober.targets))
+	}
+}
+
+func TestReconcileWithoutReadinessGate(t *testing.T) {
+	r := newTestReconciler(t, newService(), newRunnerPod())
+	module := newModule()
+	module.Status.InitializeConditions()
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if !module.Status.IsReady() {
+		t.Errorf("Module isn't ready: %+v", module.Status.Conditions)
+	}
+	if got := module.Status.ServiceName; got != testServiceName {
+		t.Errorf("Status.ServiceName = %q, want %q", got, testServiceName)
+	}
+}
+
+func TestReconcileWithoutService(t *testing.T) {
+	r := newTestReconciler(t)
+	module := newModule()
+	module.Status.ServiceName = testServiceName
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	cond := module.Status.GetCondition(api.WasmModuleConditionReady)
+	if !cond.IsFalse() || cond.Reason != api.ReasonServiceUnavailable {
+		t.Errorf("Ready = %+v, want False with reason %s", cond, api.ReasonServiceUnavailable)
+	}
+	if got := module.Status.ServiceName; got != "" {
+		t.Errorf("Status.ServiceName = %q, want it cleared", got)
+	}
+}
+
+func TestReconcileWithoutReadyPods(t *testing.T) {
+	prober := &fakeProber{}
+	pod := newRunnerPod()
+	pod.Status.Conditions[0].Status = corev1.ConditionFalse
+	r := newTestReconciler(t, newService(), pod)
+	r.Prober = prober
+	module := newModule()
+	module.Spec.ReadinessGate = &api.ReadinessGateSpec{Path: "/healthz"}
+	module.Status.InitializeConditions()
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	cond := module.Status.GetCondition(api.WasmModuleConditionReady)
+	if !cond.IsUnknown() || cond.Reason != api.ReasonNoReadyPods {
+		t.Errorf("Ready = %+v, want Unknown with reason %s", cond, api.ReasonNoReadyPods)
+	}
+	if cond := module.Status.GetCondition(api.WasmModuleConditionServiceCreated); !cond.IsTrue() {
+		t.Errorf("ServiceCreated = %+v, want True", cond)
+	}
+	if module.Status.Phase != api.WasmModulePhasePending {
+		t.Errorf("Phase = %s, want %s", module.Status.Phase, api.WasmModulePhasePending)
+	}
+	if len(prober.targets) != 0 {
+		t.Errorf("Probed %v, want no probes before pods are ready", prober.targets)
+	}
+}
+
+func TestReconcileRetriesStatusUpdateConflicts(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	module := newModule()
+	module.Generation = 2
+	client := fakeclientset.NewSimpleClientset(module)
+
+	conflicts := 0
+	client.PrependReactor("update", "wasmmodules",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "status" || conflicts > 0 {
+				return false, nil, nil
+			}
+			conflicts++
+			return true, nil, apierrs.NewConflict(api.Resource("wasmmodules"),
+				module.Name, errors.New("object has been modified"))
+		})
+
+	indexer := newIndexer()
+	if err := indexer.Add(module); err != nil {
+		t.Fatal("Error adding module to indexer:", err)
+	}
+	rec := apireconciler.NewReconciler(ctx, logtesting.TestLogger(t), client,
+		wasmlisters.NewWasmModuleLister(indexer), record.NewFakeRecorder(10),
+		newTestReconciler(t, newService(), newRunnerPod()))
+	if err := rec.(reconciler.LeaderAware).Promote(reconciler.UniversalBucket(),
+		func(reconciler.Bucket, types.NamespacedName) {}); err != nil {
+		t.Fatal("Promote() =", err)
+	}
+
+	if err := rec.Reconcile(ctx, testNamespace+"/"+testModuleName); err != nil {
+		t.Fatal("Reconcile() =", err)
+	}
+	if conflicts != 1 {
+		t.Errorf("Got %d conflicts, want 1", conflicts)
+	}
+	got, err := client.WasmV1alpha1().WasmModules(testNamespace).
+		Get(ctx, testModuleName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Error getting module:", err)
+	}
+	if !got.Status.IsReady() {
+		t.Errorf("Stored status isn't ready: %+v", got.Status.Conditions)
+	}
+	if got.Status.ObservedGeneration != module.Generation {
+		t.Errorf("ObservedGeneration = %d, want %d",
+			got.Status.ObservedGeneration, module.Generation)
+	}
+}
+
+func TestReconcileProgressDeadline(t *testing.T) {
+	created := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakeClock(created.Add(time.Minute))
+	r := newTestReconciler(t)
+	r.Clock = fakeClock
+	module := newModule()
+	module.CreationTimestamp = metav1.NewTime(created)
+	module.Spec.ProgressDeadlineSeconds = ptr.Int32(600)
+
+	err := r.ReconcileKind(context.Background(), module)
+	if ok, after := controller.IsRequeueKey(err); !ok || after != 9*time.Minute {
+		t.Errorf("ReconcileKind() = %v, want a requeue after 9m", err)
+	}
+	if got := module.Status.GetCondition(api.WasmModuleConditionReady); got.Reason == api.ReasonProgressDeadlineExceeded {
+		t.Errorf("Ready = %+v, want the deadline not yet exceeded", got)
+	}
+
+	fakeClock.Step(9 * time.Minute)
+	for i := 0; i < 2; i++ {
+		if err = r.ReconcileKind(context.Background(), module); err != nil {
+			t.Fatalf("ReconcileKind() = %v", err)
+		}
+		got := module.Status.GetCondition(api.WasmModuleConditionReady)
+		if !got.IsFalse() || got.Reason != api.ReasonProgressDeadlineExceeded {
+			t.Errorf("Ready = %+v, want False with %s", got, api.ReasonProgressDeadlineExceeded)
+		}
+	}
+}
+
+func TestReconcileProgressDeadlineAfterReady(t *testing.T) {
+	created := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakeClock(created.Add(time.Minute))
+	r := newTestReconciler(t, newService(), newRunnerPod())
+	r.Clock = fakeClock
+	module := newModule()
+	module.CreationTimestamp = metav1.NewTime(created)
+	module.Spec.ProgressDeadlineSeconds = ptr.Int32(600)
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if !module.Status.IsReady() {
+		t.Fatalf("Module isn't ready: %+v", module.Status.Conditions)
+	}
+	if got := module.Status.ProgressingSince; got != nil {
+		t.Errorf("ProgressingSince = %v, want unset while ready", got)
+	}
+
+	// The pod stops being ready an hour later, well past the deadline counted
+	// from the creation or from becoming ready.
+	fakeClock.Step(time.Hour)
+	unready := newRunnerPod()
+	unready.Status.Conditions[0].Status = corev1.ConditionFalse
+	unreadyReconciler := newTestReconciler(t, newService(), unready)
+	unreadyReconciler.Clock = fakeClock
+
+	err := unreadyReconciler.ReconcileKind(context.Background(), module)
+	if ok, after := controller.IsRequeueKey(err); !ok || after != 10*time.Minute {
+		t.Errorf("ReconcileKind() = %v, want a requeue after 10m", err)
+	}
+	if got := module.Status.GetCondition(api.WasmModuleConditionReady); got.Reason == api.ReasonProgressDeadlineExceeded {
+		t.Errorf("Ready = %+v, want the deadline not yet exceeded", got)
+	}
+	want := metav1.NewTime(fakeClock.Now())
+	if got := module.Status.ProgressingSince; got == nil || !got.Equal(&want) {
+		t.Errorf("ProgressingSince = %v, want %v", got, want)
+	}
+
+	fakeClock.Step(10 * time.Minute)
+	if err = unreadyReconciler.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	got := module.Status.GetCondition(api.WasmModuleConditionReady)
+	if !got.IsFalse() || got.Reason != api.ReasonProgressDeadlineExceeded {
+		t.Errorf("Ready = %+v, want False with %s", got, api.ReasonProgressDeadlineExceeded)
+	}
+}
+
+func TestReconcileProgressDeadlineKeepsProbing(t *testing.T) {
+	created := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	prober := &fakeProber{err: errors.New("connection refused")}
+	r := newTestReconciler(t, newService(), newRunnerPod())
+	r.Clock = clocktesting.NewFakeClock(created.Add(time.Hour))
+	r.Prober = prober
+	module := newModule()
+	module.CreationTimestamp = metav1.NewTime(created)
+	module.Spec.ProgressDeadlineSeconds = ptr.Int32(600)
+	module.Spec.ReadinessGate = &api.ReadinessGateSpec{Path: "/healthz"}
+
+	err := r.ReconcileKind(context.Background(), module)
+	if ok, after := controller.IsRequeueKey(err); !ok || after != readinessGateRetryPeriod {
+		t.Errorf("ReconcileKind() = %v, want a requeue after %v", err, readinessGateRetryPeriod)
+	}
+	got := module.Status.GetCondition(api.WasmModuleConditionReady)
+	if !got.IsFalse() || got.Reason != api.ReasonProgressDeadlineExceeded {
+		t.Errorf("Ready = %+v, want False with %s", got, api.ReasonProgressDeadlineExceeded)
+	}
+
+	prober.err = nil
+	if err = r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if !module.Status.IsReady() {
+		t.Errorf("Module isn't ready after its readiness gate passed: %+v",
+			module.Status.Conditions)
+	}
+}
+
+func TestReconcileProgressDeadlineNewGeneration(t *testing.T) {
+	created := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakeClock(created.Add(time.Hour))
+	r := newTestReconciler(t)
+	r.Clock = fakeClock
+	module := newModule()
+	module.Generation = 1
+	module.CreationTimestamp = metav1.NewTime(created)
+	module.Spec.ProgressDeadlineSeconds = ptr.Int32(600)
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	got := module.Status.GetCondition(api.WasmModuleConditionReady)
+	if !got.IsFalse() || got.Reason != api.ReasonProgressDeadlineExceeded {
+		t.Fatalf("Ready = %+v, want False with %s", got, api.ReasonProgressDeadlineExceeded)
+	}
+
+	// The stuck module is updated. The generated reconciler has stored the
+	// generation it observed.
+	module.Status.ObservedGeneration = module.Generation
+	module.Generation++
+
+	err := r.ReconcileKind(context.Background(), module)
+	if ok, after := controller.IsRequeueKey(err); !ok || after != 10*time.Minute {
+		t.Errorf("ReconcileKind() = %v, want a requeue after 10m", err)
+	}
+	if got := module.Status.GetCondition(api.WasmModuleConditionReady); got.Reason == api.ReasonProgressDeadlineExceeded {
+		t.Errorf("Ready = %+v, want the deadline not yet exceeded", got)
+	}
+	want := metav1.NewTime(fakeClock.Now())
+	if got := module.Status.ProgressingSince; got == nil || !got.Equal(&want) {
+		t.Errorf("ProgressingSince = %v, want %v", got, want)
+	}
+}
+
+func TestReconcileRestartCount(t *testing.T) {
+	selected := map[string]string{"app": testServiceName}
+	r := newTestReconciler(t, newService(),
+		newPod("runner-1", selected, 2, 1),
+		newPod("runner-2", selected, 3),
+		newPod("other", map[string]string{"app": "other"}, 7),
+	)
+	module := newModule()
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if got, want := module.Status.RestartCount, int32(6); got != want {
+		t.Errorf("RestartCount = %d, want %d", got, want)
+	}
+}
+
+func TestReconcileDeletedModule(t *testing.T) {
+	prober := &fakeProber{}
+	r := newTestReconciler(t, newService())
+	r.Prober = prober
+	module := newModule()
+	module.Spec.ReadinessGate = &api.ReadinessGateSpec{Path: "/healthz"}
+	deleted := metav1.NewTime(time.Now())
+	module.DeletionTimestamp = &deleted
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	if refs := r.Tracker.(*reconcilertesting.FakeTracker).References(); len(refs) != 0 {
+		t.Errorf("Tracked %v, want nothing tracked", refs)
+	}
+	if len(prober.targets) != 0 {
+		t.Errorf("Probed %v, want no probes", prober.targets)
+	}
+	if len(module.Status.Conditions) != 0 || module.Status.Address != nil {
+		t.Errorf("Status = %+v, want it untouched", module.Status)
+	}
+}
+
+func TestReconcileWithoutServiceName(t *testing.T) {
+	r := newTestReconciler(t, newService())
+	module := newModule()
+	// Module names may start with a digit, Service names may not.
+	module.Name = "1-reverse-text"
+	module.Spec.ServiceName = ""
+	module.Status.InitializeConditions()
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	cond := module.Status.GetCondition(api.WasmModuleConditionReady)
+	if !cond.IsFalse() || cond.Reason != api.ReasonInvalidSpec {
+		t.Errorf("Ready = %+v, want False with reason %s", cond, api.ReasonInvalidSpec)
+	}
+	if module.Status.Phase != api.WasmModulePhaseFailed {
+		t.Errorf("Phase = %s, want %s", module.Status.Phase, api.WasmModulePhaseFailed)
+	}
+	if refs := r.Tracker.(*reconcilertesting.FakeTracker).References(); len(refs) != 0 {
+		t.Errorf("Tracked %v, want nothing tracked", refs)
+	}
+}
+
+func TestReconcileRecordsLastReconcile(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 500, time.UTC)
+	r := newTestReconciler(t, newService())
+	r.Clock = clocktesting.NewFakeClock(now)
+	module := newModule()
+	module.Status.LastError = "stale error"
+
+	if err := r.ReconcileKind(context.Background(), module); err != nil {
+		t.Fatalf("ReconcileKind() = %v", err)
+	}
+	want := metav1.NewTime(now.Truncate(time.Second))
+	if got := module.Status.LastReconcileTime; got == nil || !got.Equal(&want) {
+		t.Errorf("LastReconcileTime = %v, want %v", got, want)
+	}
+	if got := module.Status.LastError; got != "" {
+		t.Errorf("LastError = %q, want it cleared", got)
+	}
+
+	trackErr := errors.New("tracker unavailable")
+	r.Tracker = &failingTracker{err: trackErr}
+	if err := r.ReconcileKind(context.Background(), module); !errors.Is(err, trackErr) {
+		t.Fatalf("ReconcileKind() = %v, want %v", err, trackErr)
+	}
+	if got := module.Status.LastError; got != trackErr.Error() {
+		t.Errorf("LastError = %q, want %q", got, trackErr.Error())
+	}
+}
+
+func TestReconcileReportsMetrics(t *testing.T) {
+	metricNames := []string{reconcileCountM.Name(), reconcileLatencyM.Name()}
+	metricstest.Unregister(metricNames...)
+	if err := view.Register(views...); err != nil {
+		t.Fatal("Error registering views:", err)
+	}
+	t.Cleanup(func() { metricstest.Unregister(metricNames...) })
+
+	r := newTestReconciler(t, newService())
+	r.Tracker = &failingTracker{err: errors.New("tracker unavailable")}
+	for i := 0; i < 2; i++ {
+		if err := r.ReconcileKind(context.Background(), newModule()); err == nil {
+			t.Fatal("ReconcileKind() = nil, want an error")
+		}
+	}
+
+	wantTags := map[string]string{
+		"result":         resultError,
+		"namespace_name": testNamespace,
+	}
+	metricstest.CheckCountData(t, reconcileCountM.Name(), wantTags, 2)
+	metricstest.CheckDistributionCount(t, reconcileLatencyM.Name(), wantTags, 2)
+}
+
+func TestReconcileResult(t *testing.T) {
+	tests := []struct {
+		event reconciler.Event
+		want  string
+	}{
+		{event: nil, want: resultSuccess},
+		{event: controller.NewRequeueAfter(time.Second), want: resultRequeue},
+		{event: errors.New("tracker unavailable"), want: resultError},
+	}
+	for _, tc := range tests {
+		if got := reconcileResult(tc.event); got != tc.want {
+			t.Errorf("reconcileResult(%v) = %s, want %s", tc.event, got, tc.want)
+		}
+	}
+}
+
+type failingTracker struct {
+	tracker.Interface
+	err error
+}
+
+func (t *failingTracker) TrackReference(tracker.Reference, interface{}) error {
+	return t.err
+}
+
+type fakeProber struct {
+	err     error
+	targets []string
+}
+
+func (p *fakeProber) Probe(_ context.Context, target string) error {
+	p.targets = append(p.targets, target)
+	return p.err
+}
+
+func newTestReconciler(t *testing.T, objs ...runtime.Object) *Reconciler {
+	t.Helper()
+	svcIndexer := newIndexer()
+	podIndexer := newIndexer()
+	for _, obj := range objs {
+		var err error
+		switch o := obj.(type) {
+		case *corev1.Service:
+			err = svcIndexer.Add(o)
+		case *corev1.Pod:
+			err = podIndexer.Add(o)
+		default:
+			t.Fatalf("Unsupported object %T", obj)
+		}
+		if err != nil {
+			t.Fatalf("Error adding %T to indexer: %v", obj, err)
+		}
+	}
+	return &Reconciler{
+		Tracker:       &reconcilertesting.FakeTracker{},
+		ServiceLister: corev1listers.NewServiceLister(svcIndexer),
+		PodLister:     corev1listers.NewPodLister(podIndexer),
+		Clock:         clock.RealClock{},
+	}
+}
+
+func newIndexer() cache.Indexer {
+	return cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+func newModule() *api.WasmModule {
+	return &api.WasmModule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testModuleName,
+			Namespace: testNamespace,
+		},
+		Spec: api.WasmModuleSpec{
+			ServiceName: testServiceName,
+		},
+	}
+}
+
+func newService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testServiceName,
+			Namespace: testNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": testServiceName},
+		},
+	}
+}
+
+func newPod(name string, podLabels map[string]string, restarts ...int32) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    podLabels,
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+	for _, r := range restarts {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses,
+			corev1.ContainerStatus{RestartCount: r})
+	}
+	return pod
+}
+
+// newRunnerPod returns a ready pod selected by the Service of newService.
+func newRunnerPod() *corev1.Pod {
+	return newPod("runner", map[string]string{"app": testServiceName})
+}
@@ -28,6 +28,13 @@ export GO111MODULE=on
 
 source $(dirname $0)/../vendor/knative.dev/hack/presubmit-tests.sh
 
+# Cargo.lock of the examples must not drift from their Cargo.toml, and
+# their code must pass clippy.
+function post_build_tests() {
+  ${REPO_ROOT_DIR}/hack/verify-cargo-lock.sh || return 1
+  ${REPO_ROOT_DIR}/hack/clippy.sh
+}
+
 # TODO(mattmoor): integration tests
 
 # We use the default build, unit and integration test runners.